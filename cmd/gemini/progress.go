@@ -0,0 +1,147 @@
+// Copyright (C) 2018 ScyllaDB
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// progressWarmupSamples is the number of initial samples that are
+// simple-averaged rather than fed into the EWMA, so that early jitter
+// (e.g. a slow first batch while connections warm up) doesn't skew the
+// smoothed rate for the rest of the run.
+const progressWarmupSamples = 5
+
+// progressRingSize bounds the window used to compute rolling percentiles
+// of the per-sample throughput.
+const progressRingSize = 64
+
+// progress tracks a smoothed view of the test throughput so it can be
+// rendered in the interactive spinner and the final result summary
+// without coupling the math to the reporter goroutine.
+type progress struct {
+	mu sync.Mutex
+
+	alpha    float64
+	duration time.Duration
+	started  time.Time
+
+	lastTotal  int
+	lastErrors int
+	lastSample time.Time
+
+	samples     int
+	rateEWMA    float64
+	errRateEWMA float64
+
+	rateRing [progressRingSize]float64
+	ringLen  int
+	ringPos  int
+}
+
+func newProgress(alpha float64, duration time.Duration) *progress {
+	return &progress{
+		alpha:    alpha,
+		duration: duration,
+		started:  time.Now(),
+	}
+}
+
+// sample records a new cumulative total of ops and errors, deriving the
+// per-sample rate from the delta since the previous sample.
+func (p *progress) sample(total, errors int, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lastSample.IsZero() {
+		p.lastTotal = total
+		p.lastErrors = errors
+		p.lastSample = now
+		return
+	}
+
+	dt := now.Sub(p.lastSample).Seconds()
+	if dt <= 0 {
+		return
+	}
+
+	rate := float64(total-p.lastTotal) / dt
+	errRate := float64(errors-p.lastErrors) / dt
+
+	p.samples++
+	if p.samples <= progressWarmupSamples {
+		p.rateEWMA += (rate - p.rateEWMA) / float64(p.samples)
+		p.errRateEWMA += (errRate - p.errRateEWMA) / float64(p.samples)
+	} else {
+		p.rateEWMA = p.alpha*rate + (1-p.alpha)*p.rateEWMA
+		p.errRateEWMA = p.alpha*errRate + (1-p.alpha)*p.errRateEWMA
+	}
+
+	p.rateRing[p.ringPos] = rate
+	p.ringPos = (p.ringPos + 1) % len(p.rateRing)
+	if p.ringLen < len(p.rateRing) {
+		p.ringLen++
+	}
+
+	p.lastTotal = total
+	p.lastErrors = errors
+	p.lastSample = now
+}
+
+// rate returns the EWMA-smoothed operations-per-second.
+func (p *progress) rate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rateEWMA
+}
+
+// errorRate returns the EWMA-smoothed errors-per-second.
+func (p *progress) errorRate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.errRateEWMA
+}
+
+// eta returns the remaining time until the configured duration elapses,
+// clamped to zero once it has passed.
+func (p *progress) eta() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	remaining := p.duration - time.Since(p.started)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// percentiles returns the p50 and p95 of the per-sample rates currently
+// held in the ring buffer.
+func (p *progress) percentiles() (p50, p95 float64) {
+	p.mu.Lock()
+	buf := make([]float64, p.ringLen)
+	copy(buf, p.rateRing[:p.ringLen])
+	p.mu.Unlock()
+
+	if len(buf) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(buf)
+	return percentile(buf, 0.50), percentile(buf, 0.95)
+}
+
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(q * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+func (p *progress) String() string {
+	p50, p95 := p.percentiles()
+	return fmt.Sprintf("rate: %.1f ops/s (err: %.1f ops/s) | p50: %.1f ops/s | p95: %.1f ops/s | eta: %s",
+		p.rate(), p.errorRate(), p50, p95, p.eta().Round(time.Second))
+}
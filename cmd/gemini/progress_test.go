@@ -0,0 +1,114 @@
+// Copyright (C) 2018 ScyllaDB
+
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestProgressSampleWarmupThenEWMA(t *testing.T) {
+	start := time.Unix(0, 0)
+	p := newProgress(0.5, time.Minute)
+	p.started = start
+
+	cases := []struct {
+		name     string
+		total    int
+		at       time.Time
+		wantRate float64
+	}{
+		{"first sample only seeds the baseline", 0, start, 0},
+		{"warmup sample 1 is a plain average", 100, start.Add(1 * time.Second), 100},
+		{"warmup sample 2 keeps averaging", 300, start.Add(2 * time.Second), 150},
+		{"warmup sample 3 keeps averaging", 600, start.Add(3 * time.Second), 200},
+		{"warmup sample 4 keeps averaging", 1000, start.Add(4 * time.Second), 250},
+		{"warmup sample 5 is the last averaged one", 1500, start.Add(5 * time.Second), 300},
+		{"sample 6 switches to the EWMA", 2100, start.Add(6 * time.Second), 450},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p.sample(c.total, 0, c.at)
+			if got := p.rate(); math.Abs(got-c.wantRate) > 1e-9 {
+				t.Fatalf("rate() = %v, want %v", got, c.wantRate)
+			}
+		})
+	}
+}
+
+func TestProgressSampleIgnoresNonPositiveDelta(t *testing.T) {
+	start := time.Unix(0, 0)
+	p := newProgress(0.5, time.Minute)
+	p.started = start
+
+	p.sample(0, 0, start)
+	p.sample(100, 0, start.Add(time.Second))
+	before := p.rate()
+
+	// A sample that doesn't advance the clock must be a no-op.
+	p.sample(500, 0, start.Add(time.Second))
+	if got := p.rate(); got != before {
+		t.Fatalf("rate() changed on a non-positive time delta: got %v, want %v", got, before)
+	}
+}
+
+func TestProgressErrorRate(t *testing.T) {
+	start := time.Unix(0, 0)
+	p := newProgress(0.5, time.Minute)
+	p.started = start
+
+	p.sample(0, 0, start)
+	p.sample(100, 10, start.Add(1*time.Second))
+	if got := p.errorRate(); got != 10 {
+		t.Fatalf("errorRate() = %v, want 10", got)
+	}
+}
+
+func TestProgressEtaClampsToZero(t *testing.T) {
+	p := newProgress(0.5, time.Minute)
+	p.started = time.Now().Add(-2 * time.Minute)
+
+	if got := p.eta(); got != 0 {
+		t.Fatalf("eta() = %v, want 0 once the duration has elapsed", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	cases := []struct {
+		name   string
+		sorted []float64
+		q      float64
+		want   float64
+	}{
+		{"empty input", nil, 0.5, 0},
+		{"p50 of five values", []float64{10, 20, 30, 40, 50}, 0.50, 30},
+		{"p95 of five values", []float64{10, 20, 30, 40, 50}, 0.95, 40},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := percentile(c.sorted, c.q); got != c.want {
+				t.Fatalf("percentile(%v, %v) = %v, want %v", c.sorted, c.q, got, c.want)
+			}
+		})
+	}
+}
+
+func TestProgressPercentilesOverRingWindow(t *testing.T) {
+	start := time.Unix(0, 0)
+	p := newProgress(0.5, time.Minute)
+	p.started = start
+
+	p.sample(0, 0, start)
+	for i := 1; i <= progressRingSize+1; i++ {
+		// A constant 10 ops/s per sample.
+		p.sample(10*i, 0, start.Add(time.Duration(i)*time.Second))
+	}
+
+	p50, p95 := p.percentiles()
+	if p50 != 10 || p95 != 10 {
+		t.Fatalf("percentiles() = (%v, %v), want (10, 10) for a constant rate", p50, p95)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2018 ScyllaDB
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scylladb/gemini"
+	"github.com/spf13/cobra"
+)
+
+// replayRecord captures everything needed to reproduce a single failing
+// statement outside of a full soak run: the statement itself, its bound
+// values rendered as CQL literals so they can be pasted into cqlsh, and in
+// Diff the error that was returned: for validation failures, the mismatch
+// observed between the test and oracle clusters.
+type replayRecord struct {
+	Seed      int64     `json:"seed"`
+	Timestamp time.Time `json:"timestamp"`
+	Table     string    `json:"table"`
+	Mode      string    `json:"mode"`
+	Statement string    `json:"statement"`
+	Values    []string  `json:"values"`
+	RangeMin  int       `json:"range_min"`
+	RangeMax  int       `json:"range_max"`
+	Diff      string    `json:"diff,omitempty"`
+}
+
+// replayWriter appends replayRecords to a JSON-lines file, guarded by a
+// mutex since it is written to from concurrent worker goroutines.
+type replayWriter struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func newReplayWriter(path string) (*replayWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &replayWriter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (w *replayWriter) append(rec replayRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(rec); err != nil {
+		fmt.Printf("cannot write replay record: %v\n", err)
+	}
+}
+
+func (w *replayWriter) Close() error {
+	return w.f.Close()
+}
+
+// cqlLiteral renders a bound value as a CQL literal, good enough to paste
+// into cqlsh for a manual repro. Embedded single quotes are doubled, CQL's
+// escaping convention, so values containing an apostrophe still round-trip.
+func cqlLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(val, "'", "''"))
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func cqlLiterals(values []interface{}) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = cqlLiteral(v)
+	}
+	return out
+}
+
+// bindLiterals substitutes each '?' placeholder in stmt, in order, with
+// the corresponding already-rendered CQL literal from values, so a
+// recorded statement can be re-executed without needing the original
+// typed bind parameters.
+func bindLiterals(stmt string, values []string) (string, error) {
+	var b strings.Builder
+	vi := 0
+	for _, r := range stmt {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		if vi >= len(values) {
+			return "", fmt.Errorf("statement has more '?' placeholders than recorded values (%d)", len(values))
+		}
+		b.WriteString(values[vi])
+		vi++
+	}
+	return b.String(), nil
+}
+
+var (
+	replayInputFile  string
+	replayTestHost   string
+	replayOracleHost string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Re-execute statements recorded by --replay-file against a cluster",
+	Run:   runReplay,
+}
+
+func runReplay(cmd *cobra.Command, args []string) {
+	f, err := os.Open(replayInputFile)
+	if err != nil {
+		fmt.Printf("cannot open replay file: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	session, err := gemini.NewSession(replayTestHost, replayOracleHost)
+	if err != nil {
+		fmt.Printf("cannot create session: %v\n", err)
+		return
+	}
+	defer session.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec replayRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			fmt.Printf("cannot parse replay record: %v\n", err)
+			continue
+		}
+		stmt, err := bindLiterals(rec.Statement, rec.Values)
+		if err != nil {
+			fmt.Printf("cannot bind replay record values: %v\n", err)
+			continue
+		}
+		fmt.Printf("replaying: %s\n", stmt)
+		switch rec.Mode {
+		case "read":
+			table := gemini.Table{Name: rec.Table}
+			if err := session.Check(table, stmt); err != nil && err != gemini.ErrReadNoDataReturned {
+				fmt.Printf("replay of seed %d against table %s failed: %v\n", rec.Seed, rec.Table, err)
+			}
+		default:
+			if err := session.Mutate(stmt); err != nil {
+				fmt.Printf("replay of seed %d against table %s failed: %v\n", rec.Seed, rec.Table, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Printf("error reading replay file: %v\n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVarP(&replayInputFile, "file", "", "", "Path to the JSON-lines replay file written by --replay-file")
+	replayCmd.MarkFlagRequired("file")
+	replayCmd.Flags().StringVarP(&replayTestHost, "test-cluster", "t", "", "Host name of the cluster to replay statements against")
+	replayCmd.MarkFlagRequired("test-cluster")
+	replayCmd.Flags().StringVarP(&replayOracleHost, "oracle-cluster", "o", "", "Host name of the oracle cluster, if a comparison is desired")
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2018 ScyllaDB
+
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// isRetryableError reports whether err is a transient CQL or connection
+// error that is worth retrying, as opposed to a syntax error or a
+// data-mismatch error from the oracle comparison, which are permanent.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch err.(type) {
+	case *gocql.RequestErrWriteTimeout, *gocql.RequestErrReadTimeout, *gocql.RequestErrUnavailable:
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, gocql.ErrConnectionClosed)
+}
+
+// retryBackoff computes the exponential backoff with jitter for the given
+// attempt: min(maxDelay, baseDelay*2^attempt) * (0.5 + rand*0.5).
+func retryBackoff(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+	delay := baseDelay * time.Duration(1<<uint(attempt))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	jitter := 0.5 + rand.Float64()*0.5
+	return time.Duration(float64(delay) * jitter)
+}
+
+// withRetry runs fn, retrying it with exponential backoff and jitter while
+// it returns a retryable error, up to maxRetries attempts on top of the
+// first. It returns the number of retries actually performed and the final
+// error (nil on success).
+func withRetry(fn func() error) (retries int, err error) {
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !isRetryableError(err) || attempt >= maxRetries {
+			return retries, err
+		}
+		retries++
+		time.Sleep(retryBackoff(attempt, retryBaseDelay, retryMaxDelay))
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2018 ScyllaDB
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// fakeNetError is a minimal net.Error so isRetryableError's errors.As branch
+// can be exercised without dialing a real connection.
+type fakeNetError struct{}
+
+func (fakeNetError) Error() string   { return "fake net error" }
+func (fakeNetError) Timeout() bool   { return true }
+func (fakeNetError) Temporary() bool { return true }
+
+func TestIsRetryableError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error is not retryable", nil, false},
+		{"plain error is not retryable", errors.New("syntax error"), false},
+		{"net.Error is retryable", fakeNetError{}, true},
+		{"error that merely mentions connection closed in its message is not retryable", errors.New("wrap: " + gocql.ErrConnectionClosed.Error()), false},
+		{"gocql.ErrConnectionClosed is retryable", gocql.ErrConnectionClosed, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryableError(c.err); got != c.want {
+				t.Fatalf("isRetryableError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffGrowsAndCapsAtMaxDelay(t *testing.T) {
+	base := 10 * time.Millisecond
+	max := 100 * time.Millisecond
+
+	cases := []struct {
+		name        string
+		attempt     int
+		wantMinimum time.Duration
+		wantMaximum time.Duration
+	}{
+		{"attempt 0 is around base delay", 0, base / 2, base},
+		{"attempt 1 doubles", 1, base, 2 * base},
+		{"attempt 2 doubles again", 2, 2 * base, 4 * base},
+		{"large attempt caps at maxDelay", 10, max / 2, max},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got := retryBackoff(c.attempt, base, max)
+				if got < c.wantMinimum || got > c.wantMaximum {
+					t.Fatalf("retryBackoff(%d, %v, %v) = %v, want in [%v, %v]",
+						c.attempt, base, max, got, c.wantMinimum, c.wantMaximum)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBackoffNeverExceedsMaxDelay(t *testing.T) {
+	base := time.Second
+	max := 2 * time.Second
+
+	for attempt := 0; attempt < 32; attempt++ {
+		for i := 0; i < 20; i++ {
+			if got := retryBackoff(attempt, base, max); got > max {
+				t.Fatalf("retryBackoff(%d, %v, %v) = %v, want <= %v", attempt, base, max, got, max)
+			}
+		}
+	}
+}
@@ -7,13 +7,20 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
-	"sync"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scylladb/gemini"
+	"github.com/scylladb/gemini/metrics"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -29,19 +36,49 @@ var (
 	failFast          bool
 	nonInteractive    bool
 	duration          time.Duration
+	progressAlpha     float64
+	metricsBind       string
+
+	testTLSCA             string
+	testTLSCert           string
+	testTLSKey            string
+	testUsername          string
+	testPassword          string
+	oracleTLSCA           string
+	oracleTLSCert         string
+	oracleTLSKey          string
+	oracleUsername        string
+	oraclePassword        string
+	tlsServerName         string
+	tlsInsecureSkipVerify bool
+
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+
+	replayFile string
+
+	partitionKeyDistribution string
 )
 
 const (
 	writeMode = "write"
 	readMode  = "read"
 	mixedMode = "mixed"
+
+	distUniform    = "uniform"
+	distZipfian    = "zipfian"
+	distHotspot    = "hotspot"
+	distSequential = "sequential"
 )
 
 type Status struct {
-	WriteOps    int
-	WriteErrors int
-	ReadOps     int
-	ReadErrors  int
+	WriteOps     int
+	WriteErrors  int
+	WriteRetries int
+	ReadOps      int
+	ReadErrors   int
+	ReadRetries  int
 }
 
 type Results interface {
@@ -53,26 +90,119 @@ func interactive() bool {
 	return !nonInteractive
 }
 
-type testJob func(context.Context, *sync.WaitGroup, *gemini.Schema, gemini.Table, *gemini.Session, gemini.PartitionRange, chan Status, string)
+type testJob func(context.Context, *gemini.Schema, gemini.Table, *gemini.Session, gemini.PartitionRange, chan Status, string, *metrics.Registry, *replayWriter) error
 
 func (r *Status) Merge(sum *Status) Status {
 	sum.WriteOps += r.WriteOps
 	sum.WriteErrors += r.WriteErrors
+	sum.WriteRetries += r.WriteRetries
 	sum.ReadOps += r.ReadOps
 	sum.ReadErrors += r.ReadErrors
+	sum.ReadRetries += r.ReadRetries
 	return *sum
 }
 
-func (r *Status) PrintResult() {
+func (r *Status) PrintResult(prog *progress) {
 	fmt.Println("Results:")
-	fmt.Printf("\twrite ops:    %v\n", r.WriteOps)
-	fmt.Printf("\tread ops:     %v\n", r.ReadOps)
-	fmt.Printf("\twrite errors: %v\n", r.WriteErrors)
-	fmt.Printf("\tread errors:  %v\n", r.ReadErrors)
+	fmt.Printf("\twrite ops:     %v\n", r.WriteOps)
+	fmt.Printf("\tread ops:      %v\n", r.ReadOps)
+	fmt.Printf("\twrite errors:  %v\n", r.WriteErrors)
+	fmt.Printf("\tread errors:   %v\n", r.ReadErrors)
+	fmt.Printf("\twrite retries: %v\n", r.WriteRetries)
+	fmt.Printf("\tread retries:  %v\n", r.ReadRetries)
+	if prog != nil {
+		fmt.Printf("\t%s\n", prog)
+	}
 }
 
 func (r Status) String() string {
-	return fmt.Sprintf("write ops: %v | read ops: %v | write errors: %v | read errors: %v", r.WriteOps, r.ReadOps, r.WriteErrors, r.ReadErrors)
+	return fmt.Sprintf("write ops: %v | read ops: %v | write errors: %v | read errors: %v | write retries: %v | read retries: %v",
+		r.WriteOps, r.ReadOps, r.WriteErrors, r.ReadErrors, r.WriteRetries, r.ReadRetries)
+}
+
+// schemaReport is what run() prints so that a test run can be reproduced
+// exactly: the generated/loaded schema plus the partition key
+// distribution (and its parameters) used to drive it.
+// PartitionKeyDistributionSpec is the value that can be pasted back into
+// --partition-key-distribution; PartitionKeyDistribution is the same
+// information rendered for a human to read.
+type schemaReport struct {
+	*gemini.Schema
+	PartitionKeyDistribution     string `json:"partition_key_distribution"`
+	PartitionKeyDistributionSpec string `json:"partition_key_distribution_spec"`
+}
+
+// newDistribution parses spec and returns a factory that builds the
+// gemini.Distribution it names for a partition range of size n. spec is
+// "<name>" or "<name>:k=v[,k=v...]", e.g. "zipfian:theta=1.1" or
+// "hotspot:fraction=0.1,weight=0.9". A factory, rather than a single
+// instance, is returned so that every worker can get its own: Sequential
+// keeps a mutable counter, and sharing one instance across the
+// concurrent worker goroutines of runJob would mean the shared counter,
+// not each worker's own range, decides which key comes next, defeating
+// Sequential's in-order-walk guarantee. Uniform, Hotspot and Zipfian hold
+// no mutable state after construction, so their factories just return
+// the one instance built up front.
+func newDistribution(spec string, n int) (func() gemini.Distribution, error) {
+	name, params, err := parseDistributionSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "", distUniform:
+		d := gemini.Uniform{N: n}
+		return func() gemini.Distribution { return d }, nil
+	case distSequential:
+		return func() gemini.Distribution { return &gemini.Sequential{N: n} }, nil
+	case distZipfian:
+		theta := 0.99
+		if v, ok := params["theta"]; ok {
+			if theta, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("invalid zipfian theta %q: %v", v, err)
+			}
+		}
+		d := gemini.NewZipfian(n, theta)
+		return func() gemini.Distribution { return d }, nil
+	case distHotspot:
+		fraction, weight := 0.1, 0.9
+		if v, ok := params["fraction"]; ok {
+			if fraction, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("invalid hotspot fraction %q: %v", v, err)
+			}
+		}
+		if v, ok := params["weight"]; ok {
+			if weight, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("invalid hotspot weight %q: %v", v, err)
+			}
+		}
+		d := gemini.Hotspot{N: n, HotFraction: fraction, HotWeight: weight}
+		return func() gemini.Distribution { return d }, nil
+	default:
+		return nil, fmt.Errorf("unknown partition key distribution %q", name)
+	}
+}
+
+// parseDistributionSpec splits a "<name>:k=v,k=v" spec into its name and
+// parameter map. A spec with no ':' is just a name with no parameters.
+func parseDistributionSpec(spec string) (string, map[string]string, error) {
+	name := spec
+	params := map[string]string{}
+	idx := strings.IndexByte(spec, ':')
+	if idx < 0 {
+		return name, params, nil
+	}
+	name = spec[:idx]
+	for _, kv := range strings.Split(spec[idx+1:], ",") {
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return "", nil, fmt.Errorf("invalid distribution parameter %q", kv)
+		}
+		params[parts[0]] = parts[1]
+	}
+	return name, params, nil
 }
 
 func readSchema(confFile string) (*gemini.Schema, error) {
@@ -96,7 +226,7 @@ func readSchema(confFile string) (*gemini.Schema, error) {
 	return schemaBuilder.Build(), nil
 }
 
-func run(cmd *cobra.Command, args []string) {
+func run(cmd *cobra.Command, args []string) error {
 	rand.Seed(int64(seed))
 	fmt.Printf("Seed:                            %d\n", seed)
 	fmt.Printf("Maximum duration:                %s\n", duration)
@@ -110,17 +240,51 @@ func run(cmd *cobra.Command, args []string) {
 		var err error
 		schema, err = readSchema(schemaFile)
 		if err != nil {
-			fmt.Printf("cannot create schema: %v", err)
-			return
+			return fmt.Errorf("cannot create schema: %v", err)
 		}
 	} else {
 		schema = gemini.GenSchema()
 	}
 
-	jsonSchema, _ := json.MarshalIndent(schema, "", "    ")
+	newDist, err := newDistribution(partitionKeyDistribution, pkNumberPerThread)
+	if err != nil {
+		return fmt.Errorf("cannot create partition key distribution: %v", err)
+	}
+	distSpec := partitionKeyDistribution
+	if distSpec == "" {
+		distSpec = distUniform
+	}
+
+	jsonSchema, _ := json.MarshalIndent(schemaReport{
+		Schema:                       schema,
+		PartitionKeyDistribution:     newDist().Name(),
+		PartitionKeyDistributionSpec: distSpec,
+	}, "", "    ")
 	fmt.Printf("Schema: %v\n", string(jsonSchema))
 
-	session := gemini.NewSession(testClusterHost, oracleClusterHost)
+	session, err := gemini.NewSession(testClusterHost, oracleClusterHost,
+		gemini.WithTestClusterTLS(gemini.ClusterTLSConfig{
+			CAPath:             testTLSCA,
+			CertPath:           testTLSCert,
+			KeyPath:            testTLSKey,
+			ServerName:         tlsServerName,
+			InsecureSkipVerify: tlsInsecureSkipVerify,
+			Username:           testUsername,
+			Password:           testPassword,
+		}),
+		gemini.WithOracleClusterTLS(gemini.ClusterTLSConfig{
+			CAPath:             oracleTLSCA,
+			CertPath:           oracleTLSCert,
+			KeyPath:            oracleTLSKey,
+			ServerName:         tlsServerName,
+			InsecureSkipVerify: tlsInsecureSkipVerify,
+			Username:           oracleUsername,
+			Password:           oraclePassword,
+		}),
+	)
+	if err != nil {
+		return err
+	}
 	defer session.Close()
 
 	if dropSchema && mode != readMode {
@@ -129,8 +293,7 @@ func run(cmd *cobra.Command, args []string) {
 				fmt.Println(stmt)
 			}
 			if err := session.Mutate(stmt); err != nil {
-				fmt.Printf("%v", err)
-				return
+				return err
 			}
 		}
 	}
@@ -139,43 +302,83 @@ func run(cmd *cobra.Command, args []string) {
 			fmt.Println(stmt)
 		}
 		if err := session.Mutate(stmt); err != nil {
-			fmt.Printf("%v", err)
-			return
+			return err
 		}
 	}
 
-	runJob(Job, schema, session, mode)
+	return runJob(Job, schema, session, mode, newDist)
 }
 
-func runJob(f testJob, schema *gemini.Schema, s *gemini.Session, mode string) {
-	c := make(chan Status)
+// runJob fans out one worker goroutine per table per concurrency slot
+// plus a reporter goroutine, all under an errgroup.Group so that Ctrl-C,
+// a SIGTERM (e.g. from `docker stop`), the test duration expiring, or a
+// fail-fast read error all funnel through the same cancellation path. It
+// returns a non-nil error if the run was cut short by a worker error or
+// ended with write/read errors recorded, so callers can surface a
+// non-zero exit code.
+func runJob(f testJob, schema *gemini.Schema, s *gemini.Session, mode string, newDist func() gemini.Distribution) error {
 	minRange := 0
 	maxRange := pkNumberPerThread
 
-	// Wait group for the worker goroutines.
-	var workers sync.WaitGroup
-	workerCtx, cancelWorkers := context.WithCancel(context.Background())
-	workers.Add(len(schema.Tables) * concurrency)
+	// Buffered so that workers rarely wait on a busy reporter. Job's
+	// periodic mid-loop flush is a non-blocking send (it just keeps
+	// accumulating locally if the buffer is full). Once the reporter has
+	// stopped reading, runJob keeps draining c until every worker has
+	// returned, so a worker's final ctx.Done() flush can never block
+	// forever.
+	workerCount := len(schema.Tables) * concurrency
+	c := make(chan Status, workerCount)
+
+	registry := metrics.NewRegistry()
+	if metricsBind != "" {
+		srv, err := metrics.Serve(metricsBind)
+		if err != nil {
+			fmt.Printf("cannot start metrics endpoint on %s: %v\n", metricsBind, err)
+		} else {
+			fmt.Printf("Metrics:                         http://%s/metrics\n", metricsBind)
+			defer srv.Close()
+		}
+	}
+
+	var replay *replayWriter
+	if replayFile != "" {
+		var err error
+		replay, err = newReplayWriter(replayFile)
+		if err != nil {
+			fmt.Printf("cannot open replay file %s: %v\n", replayFile, err)
+		} else {
+			defer replay.Close()
+		}
+	}
+
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	g, gctx := errgroup.WithContext(sigCtx)
+	workCtx, cancelWork := context.WithCancel(gctx)
+	defer cancelWork()
 
 	for _, table := range schema.Tables {
+		table := table
 		for i := 0; i < concurrency; i++ {
 			p := gemini.PartitionRange{
-				Min:  minRange + i*maxRange,
-				Max:  maxRange + i*maxRange,
-				Rand: rand.New(rand.NewSource(int64(seed))),
+				Min:          minRange + i*maxRange,
+				Max:          maxRange + i*maxRange,
+				Rand:         rand.New(rand.NewSource(int64(seed))),
+				Distribution: newDist(),
 			}
-			go f(workerCtx, &workers, schema, table, s, p, c, mode)
+			g.Go(func() error {
+				return f(workCtx, schema, table, s, p, c, mode, registry, replay)
+			})
 		}
 	}
 
-	// Wait group for the reporter goroutine.
-	var reporter sync.WaitGroup
-	reporter.Add(1)
-	reporterCtx, cancelReporter := context.WithCancel(context.Background())
+	reporterDone := make(chan Status, 1)
 	go func(d time.Duration) {
-		defer reporter.Done()
 		var testRes Status
+		prog := newProgress(progressAlpha, d)
 		timer := time.NewTimer(d)
+		defer timer.Stop()
 		var sp *spinner.Spinner = nil
 		if interactive() {
 			spinnerCharSet := []string{"|", "/", "-", "\\"}
@@ -187,23 +390,28 @@ func runJob(f testJob, schema *gemini.Schema, s *gemini.Session, mode string) {
 		for {
 			select {
 			case <-timer.C:
-				testRes.PrintResult()
+				testRes.PrintResult(prog)
 				fmt.Println("Test run completed. Exiting.")
-				cancelWorkers()
+				cancelWork()
+				reporterDone <- testRes
 				return
-			case <-reporterCtx.Done():
-				testRes.PrintResult()
+			case <-workCtx.Done():
+				testRes.PrintResult(prog)
+				reporterDone <- testRes
 				return
 			case res := <-c:
 				testRes = res.Merge(&testRes)
+				prog.sample(testRes.WriteOps+testRes.ReadOps, testRes.WriteErrors+testRes.ReadErrors, time.Now())
+				registry.OpRate.Set(prog.rate())
 				if sp != nil {
-					sp.Suffix = fmt.Sprintf(" Running Gemini... %v", testRes)
+					sp.Suffix = fmt.Sprintf(" Running Gemini... %v | %s", testRes, prog)
 				}
 				if testRes.ReadErrors > 0 {
-					testRes.PrintResult()
+					testRes.PrintResult(prog)
 					if failFast {
 						fmt.Println("Error in data validation. Exiting.")
-						cancelWorkers()
+						cancelWork()
+						reporterDone <- testRes
 						return
 					}
 				}
@@ -211,13 +419,49 @@ func runJob(f testJob, schema *gemini.Schema, s *gemini.Session, mode string) {
 		}
 	}(duration)
 
-	workers.Wait()
-	cancelReporter()
-	reporter.Wait()
+	testRes := <-reporterDone
+	// The reporter no longer reads c; drain it until every worker returns.
+	waitDone := make(chan error, 1)
+	go func() { waitDone <- g.Wait() }()
+	var workErr error
+	for waiting := true; waiting; {
+		select {
+		case <-c:
+		case workErr = <-waitDone:
+			waiting = false
+		}
+	}
+
+	if sigCtx.Err() != nil {
+		fmt.Println("Received interrupt signal. Shut down cleanly.")
+	}
+
+	if workErr != nil {
+		return workErr
+	}
+	if testRes.WriteErrors > 0 || testRes.ReadErrors > 0 {
+		return fmt.Errorf("test run completed with %d write errors and %d read errors", testRes.WriteErrors, testRes.ReadErrors)
+	}
+	return nil
+}
+
+// pinnedRange draws the next partition key from p according to its
+// Distribution and narrows the range to that single key, so that
+// whatever key selection GenMutateStmt/GenCheckStmt do internally can't
+// override the Distribution's choice.
+func pinnedRange(p gemini.PartitionRange) gemini.PartitionRange {
+	key := p.Next()
+	return gemini.PartitionRange{
+		Min:          key,
+		Max:          key + 1,
+		Rand:         p.Rand,
+		Distribution: gemini.Uniform{N: 1},
+	}
 }
 
-func mutationJob(schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, testStatus *Status) {
-	mutateStmt, err := schema.GenMutateStmt(table, &p)
+func mutationJob(schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, testStatus *Status, registry *metrics.Registry, replay *replayWriter) {
+	keyRange := pinnedRange(p)
+	mutateStmt, err := schema.GenMutateStmt(table, &keyRange)
 	if err != nil {
 		fmt.Printf("Failed! Mutation statement generation failed: '%v'\n", err)
 		testStatus.WriteErrors++
@@ -229,75 +473,135 @@ func mutationJob(schema *gemini.Schema, table gemini.Table, s *gemini.Session, p
 		fmt.Printf("%s (values=%v)\n", mutateQuery, mutateValues)
 	}
 	testStatus.WriteOps++
-	if err := s.Mutate(mutateQuery, mutateValues...); err != nil {
+	labels := prometheus.Labels{"keyspace": schema.Keyspace.Name, "table": table.Name}
+	var attemptDuration time.Duration
+	retries, err := withRetry(func() error {
+		start := time.Now()
+		err := s.Mutate(mutateQuery, mutateValues...)
+		attemptDuration = time.Since(start)
+		return err
+	})
+	registry.MutationTime.With(labels).Observe(attemptDuration.Seconds())
+	testStatus.WriteRetries += retries
+	registry.WriteOps.With(labels).Inc()
+	if err != nil {
 		fmt.Printf("Failed! Mutation '%s' (values=%v) caused an error: '%v'\n", mutateQuery, mutateValues, err)
 		testStatus.WriteErrors++
+		registry.WriteErrors.With(labels).Inc()
+		if replay != nil {
+			replay.append(replayRecord{
+				Seed:      int64(seed),
+				Timestamp: time.Now(),
+				Table:     table.Name,
+				Mode:      "write",
+				Statement: mutateQuery,
+				Values:    cqlLiterals(mutateValues),
+				RangeMin:  p.Min,
+				RangeMax:  p.Max,
+				Diff:      err.Error(),
+			})
+		}
 	}
 }
 
-func validationJob(schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, testStatus *Status) {
-	checkStmt := schema.GenCheckStmt(table, &p)
+func validationJob(schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, testStatus *Status, registry *metrics.Registry, replay *replayWriter) {
+	keyRange := pinnedRange(p)
+	checkStmt := schema.GenCheckStmt(table, &keyRange)
 	checkQuery := checkStmt.Query
 	checkValues := checkStmt.Values()
 	if verbose {
 		fmt.Printf("%s (values=%v)\n", checkQuery, checkValues)
 	}
-	err := s.Check(table, checkQuery, checkValues...)
+	labels := prometheus.Labels{"keyspace": schema.Keyspace.Name, "table": table.Name}
+	var attemptDuration time.Duration
+	retries, err := withRetry(func() error {
+		start := time.Now()
+		err := s.Check(table, checkQuery, checkValues...)
+		attemptDuration = time.Since(start)
+		return err
+	})
+	registry.ValidateTime.With(labels).Observe(attemptDuration.Seconds())
+	testStatus.ReadRetries += retries
 	if err == nil {
 		testStatus.ReadOps++
+		registry.ReadOps.With(labels).Inc()
 	} else {
 		if err != gemini.ErrReadNoDataReturned {
 			fmt.Printf("Failed! Check '%s' (values=%v)\n%s\n", checkQuery, checkValues, err)
 			testStatus.ReadErrors++
+			registry.ReadErrors.With(labels).Inc()
+			if replay != nil {
+				replay.append(replayRecord{
+					Seed:      int64(seed),
+					Timestamp: time.Now(),
+					Table:     table.Name,
+					Mode:      "read",
+					Statement: checkQuery,
+					Values:    cqlLiterals(checkValues),
+					RangeMin:  p.Min,
+					RangeMax:  p.Max,
+					Diff:      err.Error(),
+				})
+			}
 		}
 	}
 }
 
-func Job(ctx context.Context, wg *sync.WaitGroup, schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, c chan Status, mode string) {
-	defer wg.Done()
+func Job(ctx context.Context, schema *gemini.Schema, table gemini.Table, s *gemini.Session, p gemini.PartitionRange, c chan Status, mode string, registry *metrics.Registry, replay *replayWriter) error {
+	registry.Workers.Inc()
+	defer registry.Workers.Dec()
 	testStatus := Status{}
 
 	var i int
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			c <- testStatus
+			return nil
 		default:
 		}
 		switch mode {
 		case writeMode:
-			mutationJob(schema, table, s, p, &testStatus)
+			mutationJob(schema, table, s, p, &testStatus, registry, replay)
 		case readMode:
-			validationJob(schema, table, s, p, &testStatus)
+			validationJob(schema, table, s, p, &testStatus, registry, replay)
 		default:
 			ind := p.Rand.Intn(100000) % 2
 			if ind == 0 {
-				mutationJob(schema, table, s, p, &testStatus)
+				mutationJob(schema, table, s, p, &testStatus, registry, replay)
 			} else {
-				validationJob(schema, table, s, p, &testStatus)
+				validationJob(schema, table, s, p, &testStatus, registry, replay)
 			}
 		}
 
 		if i%1000 == 0 {
-			c <- testStatus
-			testStatus = Status{}
+			// Non-blocking: if the reporter has already stopped reading
+			// (shutdown in progress), keep accumulating locally rather
+			// than piling up a send behind it.
+			select {
+			case c <- testStatus:
+				testStatus = Status{}
+			default:
+			}
 		}
 		if failFast && testStatus.ReadErrors > 0 {
-			break
+			c <- testStatus
+			return fmt.Errorf("worker for table %s stopped after %d read errors", table.Name, testStatus.ReadErrors)
 		}
 		i++
 	}
-
-	c <- testStatus
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "gemini",
 	Short: "Gemini is an automatic random testing tool for Scylla.",
-	Run:   run,
+	RunE:  run,
 }
 
 func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
 
 func init() {
@@ -317,4 +621,23 @@ func init() {
 	rootCmd.Flags().BoolVarP(&failFast, "fail-fast", "f", false, "Stop on the first failure")
 	rootCmd.Flags().BoolVarP(&nonInteractive, "non-interactive", "", false, "Run in non-interactive mode (disable progress indicator)")
 	rootCmd.Flags().DurationVarP(&duration, "duration", "", 30*time.Second, "")
+	rootCmd.Flags().Float64VarP(&progressAlpha, "progress-alpha", "", 0.5, "Smoothing factor (0-1) for the EWMA throughput estimate shown in the progress reporter")
+	rootCmd.Flags().StringVarP(&metricsBind, "metrics-bind", "", "", "Address to bind the Prometheus /metrics endpoint to, e.g. ':2112'. Disabled when empty")
+	rootCmd.Flags().StringVarP(&testTLSCA, "test-tls-ca", "", "", "Path to the CA certificate used to verify the test cluster")
+	rootCmd.Flags().StringVarP(&testTLSCert, "test-tls-cert", "", "", "Path to the client certificate used to authenticate against the test cluster")
+	rootCmd.Flags().StringVarP(&testTLSKey, "test-tls-key", "", "", "Path to the client key used to authenticate against the test cluster")
+	rootCmd.Flags().StringVarP(&testUsername, "test-username", "", "", "Username for password authentication against the test cluster")
+	rootCmd.Flags().StringVarP(&testPassword, "test-password", "", "", "Password for password authentication against the test cluster")
+	rootCmd.Flags().StringVarP(&oracleTLSCA, "oracle-tls-ca", "", "", "Path to the CA certificate used to verify the oracle cluster")
+	rootCmd.Flags().StringVarP(&oracleTLSCert, "oracle-tls-cert", "", "", "Path to the client certificate used to authenticate against the oracle cluster")
+	rootCmd.Flags().StringVarP(&oracleTLSKey, "oracle-tls-key", "", "", "Path to the client key used to authenticate against the oracle cluster")
+	rootCmd.Flags().StringVarP(&oracleUsername, "oracle-username", "", "", "Username for password authentication against the oracle cluster")
+	rootCmd.Flags().StringVarP(&oraclePassword, "oracle-password", "", "", "Password for password authentication against the oracle cluster")
+	rootCmd.Flags().StringVarP(&tlsServerName, "tls-server-name", "", "", "Server name used for TLS certificate verification, if different from the cluster host")
+	rootCmd.Flags().BoolVarP(&tlsInsecureSkipVerify, "tls-insecure-skip-verify", "", false, "Skip TLS certificate verification for the test and oracle clusters")
+	rootCmd.Flags().IntVarP(&maxRetries, "max-retries", "", 3, "Maximum number of retries for transient CQL errors before counting a write/read as failed")
+	rootCmd.Flags().DurationVarP(&retryBaseDelay, "retry-base-delay", "", 100*time.Millisecond, "Base delay for the exponential backoff between retries")
+	rootCmd.Flags().DurationVarP(&retryMaxDelay, "retry-max-delay", "", 2*time.Second, "Maximum delay for the exponential backoff between retries")
+	rootCmd.Flags().StringVarP(&replayFile, "replay-file", "", "", "Path to a JSON-lines file to append failing statements to, for later replay with 'gemini replay'")
+	rootCmd.Flags().StringVarP(&partitionKeyDistribution, "partition-key-distribution", "", distUniform, "Distribution for picking partition keys within a worker's range: uniform, sequential, zipfian[:theta=0.99], hotspot[:fraction=0.1,weight=0.9]")
 }
@@ -0,0 +1,115 @@
+// Copyright (C) 2018 ScyllaDB
+
+package gemini
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// Distribution draws the next partition key index in [0, N) for some N,
+// modelling a particular access pattern over a worker's partition range.
+type Distribution interface {
+	Next(r *rand.Rand) int
+	Name() string
+}
+
+// Uniform draws uniformly over [0, N), the behavior PartitionRange had
+// before Distribution was introduced.
+type Uniform struct {
+	N int
+}
+
+func (u Uniform) Next(r *rand.Rand) int { return r.Intn(u.N) }
+func (u Uniform) Name() string          { return "uniform" }
+
+// Sequential walks [0, N) in order, wrapping around, useful for
+// reproducing bugs that depend on insertion order rather than randomness.
+type Sequential struct {
+	N    int
+	next uint64
+}
+
+func (s *Sequential) Next(r *rand.Rand) int {
+	i := atomic.AddUint64(&s.next, 1) - 1
+	return int(i % uint64(s.N))
+}
+func (s *Sequential) Name() string { return "sequential" }
+
+// Hotspot sends hotWeight of the traffic to a hotFraction-sized slice of
+// [0, N), the classic way to stress a small set of partitions the way a
+// skewed production workload would.
+type Hotspot struct {
+	N           int
+	HotFraction float64
+	HotWeight   float64
+}
+
+func (h Hotspot) Next(r *rand.Rand) int {
+	hotSize := int(float64(h.N) * h.HotFraction)
+	if hotSize < 1 {
+		hotSize = 1
+	}
+	if hotSize >= h.N {
+		return r.Intn(h.N)
+	}
+	if r.Float64() < h.HotWeight {
+		return r.Intn(hotSize)
+	}
+	return hotSize + r.Intn(h.N-hotSize)
+}
+
+func (h Hotspot) Name() string {
+	return fmt.Sprintf("hotspot(fraction=%.2f,weight=%.2f)", h.HotFraction, h.HotWeight)
+}
+
+// Zipfian is the standard Gray et al. generator: it precomputes the zeta
+// sum for n items so that each draw afterwards is O(1), using the same
+// fast approximation as the original paper (and YCSB's ZipfianGenerator)
+// rather than a linear scan of the partial sums.
+type Zipfian struct {
+	n     int
+	theta float64
+	zetan float64
+	alpha float64
+	eta   float64
+}
+
+// NewZipfian precomputes the zeta sum for n items at the given theta.
+func NewZipfian(n int, theta float64) *Zipfian {
+	zetan := zeta(n, theta)
+	zeta2 := zeta(2, theta)
+	alpha := 1.0 / (1.0 - theta)
+	eta := (1 - math.Pow(2.0/float64(n), 1-theta)) / (1 - zeta2/zetan)
+	return &Zipfian{n: n, theta: theta, zetan: zetan, alpha: alpha, eta: eta}
+}
+
+func zeta(n int, theta float64) float64 {
+	var sum float64
+	for i := 1; i <= n; i++ {
+		sum += 1 / math.Pow(float64(i), theta)
+	}
+	return sum
+}
+
+func (z *Zipfian) Next(r *rand.Rand) int {
+	u := r.Float64()
+	uz := u * z.zetan
+	if uz < 1 {
+		return 0
+	}
+	if uz < 1+math.Pow(0.5, z.theta) {
+		return 1
+	}
+	i := int(float64(z.n) * math.Pow(z.eta*u-z.eta+1, z.alpha))
+	if i >= z.n {
+		i = z.n - 1
+	}
+	return i
+}
+
+func (z *Zipfian) Name() string {
+	return fmt.Sprintf("zipfian(theta=%.2f)", z.theta)
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2018 ScyllaDB
+
+package gemini
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSequentialWrapsAndWalksInOrder(t *testing.T) {
+	s := &Sequential{N: 3}
+	r := rand.New(rand.NewSource(1))
+
+	want := []int{0, 1, 2, 0, 1, 2, 0}
+	for i, w := range want {
+		if got := s.Next(r); got != w {
+			t.Fatalf("draw %d: Next() = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestSequentialIndependentCountersDoNotInterfere(t *testing.T) {
+	a := &Sequential{N: 2}
+	b := &Sequential{N: 2}
+	r := rand.New(rand.NewSource(1))
+
+	if got := a.Next(r); got != 0 {
+		t.Fatalf("a.Next() = %v, want 0", got)
+	}
+	if got := b.Next(r); got != 0 {
+		t.Fatalf("b.Next() = %v, want 0 (fresh instance, unaffected by a's counter)", got)
+	}
+	if got := a.Next(r); got != 1 {
+		t.Fatalf("a.Next() = %v, want 1", got)
+	}
+}
+
+func TestHotspotWithinBounds(t *testing.T) {
+	cases := []struct {
+		name        string
+		n           int
+		hotFraction float64
+		hotWeight   float64
+	}{
+		{"typical skew", 100, 0.1, 0.9},
+		{"hotFraction rounds down to zero still gets at least one key", 10, 0.01, 0.9},
+		{"hotFraction covers the whole range", 10, 1.0, 0.9},
+		{"hotFraction exceeds the whole range", 10, 2.0, 0.9},
+		{"single key range", 1, 0.1, 0.9},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Hotspot{N: c.n, HotFraction: c.hotFraction, HotWeight: c.hotWeight}
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 1000; i++ {
+				got := h.Next(r)
+				if got < 0 || got >= c.n {
+					t.Fatalf("Next() = %v, want in [0, %v)", got, c.n)
+				}
+			}
+		})
+	}
+}
+
+func TestZipfianWithinBounds(t *testing.T) {
+	cases := []struct {
+		name  string
+		n     int
+		theta float64
+	}{
+		{"small n", 2, 0.99},
+		{"typical n", 10000, 0.99},
+		{"low theta (near-uniform)", 10000, 0.1},
+		{"high theta (sharply skewed)", 10000, 1.5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			z := NewZipfian(c.n, c.theta)
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 1000; i++ {
+				got := z.Next(r)
+				if got < 0 || got >= c.n {
+					t.Fatalf("Next() = %v, want in [0, %v)", got, c.n)
+				}
+			}
+		})
+	}
+}
+
+func TestUniformWithinBounds(t *testing.T) {
+	u := Uniform{N: 5}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if got := u.Next(r); got < 0 || got >= 5 {
+			t.Fatalf("Next() = %v, want in [0, 5)", got)
+		}
+	}
+}
+
+func TestDistributionNames(t *testing.T) {
+	cases := []struct {
+		name string
+		dist Distribution
+		want string
+	}{
+		{"uniform", Uniform{N: 10}, "uniform"},
+		{"sequential", &Sequential{N: 10}, "sequential"},
+		{"hotspot", Hotspot{N: 10, HotFraction: 0.1, HotWeight: 0.9}, "hotspot(fraction=0.10,weight=0.90)"},
+		{"zipfian", NewZipfian(10, 0.99), "zipfian(theta=0.99)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.dist.Name(); got != c.want {
+				t.Fatalf("Name() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
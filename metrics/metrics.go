@@ -0,0 +1,87 @@
+// Copyright (C) 2018 ScyllaDB
+
+// Package metrics exposes gemini's counters, histograms and gauges on a
+// Prometheus /metrics endpoint so a long-running soak or a CI job can be
+// scraped instead of relying solely on the interactive progress reporter.
+package metrics
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry bundles all of the metrics gemini reports. It is safe for
+// concurrent use by the worker and reporter goroutines.
+type Registry struct {
+	WriteOps     *prometheus.CounterVec
+	ReadOps      *prometheus.CounterVec
+	WriteErrors  *prometheus.CounterVec
+	ReadErrors   *prometheus.CounterVec
+	MutationTime *prometheus.HistogramVec
+	ValidateTime *prometheus.HistogramVec
+	Workers      prometheus.Gauge
+	OpRate       prometheus.Gauge
+}
+
+// NewRegistry registers and returns a fresh set of gemini metrics.
+func NewRegistry() *Registry {
+	labels := []string{"keyspace", "table"}
+	return &Registry{
+		WriteOps: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "gemini_write_ops_total",
+			Help: "Total number of mutation statements executed.",
+		}, labels),
+		ReadOps: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "gemini_read_ops_total",
+			Help: "Total number of validation statements that completed without error.",
+		}, labels),
+		WriteErrors: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "gemini_write_errors_total",
+			Help: "Total number of mutation statements that returned an error.",
+		}, labels),
+		ReadErrors: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "gemini_read_errors_total",
+			Help: "Total number of validation statements that returned a mismatch or error.",
+		}, labels),
+		MutationTime: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gemini_mutation_duration_seconds",
+			Help:    "Latency of mutation statements against the test cluster.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		ValidateTime: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gemini_validation_duration_seconds",
+			Help:    "Latency of validation statements comparing test and oracle clusters.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		Workers: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "gemini_workers_running",
+			Help: "Number of worker goroutines currently running.",
+		}),
+		OpRate: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "gemini_op_rate",
+			Help: "EWMA-smoothed operations per second, as shown in the progress reporter.",
+		}),
+	}
+}
+
+// Serve starts an HTTP server exposing /metrics on bind and returns
+// immediately; the caller is responsible for stopping it via the
+// returned server's Shutdown/Close.
+func Serve(bind string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: bind, Handler: mux}
+
+	ln, err := net.Listen("tcp", bind)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	return srv, nil
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2018 ScyllaDB
+
+package gemini
+
+import "math/rand"
+
+// PartitionRange describes one worker's partition key range: [Min, Max)
+// plus the source of randomness and access-pattern Distribution used to
+// pick a key within it. Distribution defaults to Uniform when unset, so
+// existing callers that only set Min/Max/Rand keep their previous
+// behavior.
+type PartitionRange struct {
+	Min          int
+	Max          int
+	Rand         *rand.Rand
+	Distribution Distribution
+}
+
+// Next draws the next partition key within [Min, Max) according to
+// Distribution.
+func (p PartitionRange) Next() int {
+	dist := p.Distribution
+	if dist == nil {
+		dist = Uniform{N: p.Max - p.Min}
+	}
+	return p.Min + dist.Next(p.Rand)
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2018 ScyllaDB
+
+package gemini
+
+import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+
+	"github.com/gocql/gocql"
+)
+
+// ClusterTLSConfig holds the optional TLS and authentication settings used
+// to build a gocql.ClusterConfig for a single cluster (test or oracle).
+// The zero value disables TLS and auth, preserving the previous
+// plain-connection behavior.
+type ClusterTLSConfig struct {
+	CAPath             string
+	CertPath           string
+	KeyPath            string
+	ServerName         string
+	InsecureSkipVerify bool
+	Username           string
+	Password           string
+}
+
+func (c ClusterTLSConfig) tlsEnabled() bool {
+	return c.CAPath != "" || c.CertPath != "" || c.KeyPath != ""
+}
+
+func (c ClusterTLSConfig) apply(cluster *gocql.ClusterConfig) {
+	if c.tlsEnabled() {
+		cluster.SslOpts = &gocql.SslOptions{
+			CaPath:                 c.CAPath,
+			CertPath:               c.CertPath,
+			KeyPath:                c.KeyPath,
+			EnableHostVerification: !c.InsecureSkipVerify,
+			Config: &tls.Config{
+				ServerName:         c.ServerName,
+				InsecureSkipVerify: c.InsecureSkipVerify,
+			},
+		}
+	}
+	if c.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: c.Username,
+			Password: c.Password,
+		}
+	}
+}
+
+// SessionConfig configures the TLS and auth settings for the test and
+// oracle clusters built by NewSession.
+type SessionConfig struct {
+	TestTLS   ClusterTLSConfig
+	OracleTLS ClusterTLSConfig
+}
+
+// SessionOption customises a SessionConfig. Use WithTestClusterTLS and
+// WithOracleClusterTLS to enable client-cert or username/password auth
+// against clusters that require it.
+type SessionOption func(*SessionConfig)
+
+// WithTestClusterTLS sets the TLS/auth options used to connect to the test
+// cluster.
+func WithTestClusterTLS(c ClusterTLSConfig) SessionOption {
+	return func(sc *SessionConfig) { sc.TestTLS = c }
+}
+
+// WithOracleClusterTLS sets the TLS/auth options used to connect to the
+// oracle cluster.
+func WithOracleClusterTLS(c ClusterTLSConfig) SessionOption {
+	return func(sc *SessionConfig) { sc.OracleTLS = c }
+}
+
+func buildCluster(host string, tlsCfg ClusterTLSConfig) *gocql.ClusterConfig {
+	cluster := gocql.NewCluster(host)
+	tlsCfg.apply(cluster)
+	return cluster
+}
+
+// NewSession creates the test and, when oracleClusterHost is non-empty,
+// oracle gocql sessions used to drive a gemini run. By default it connects
+// without TLS or authentication; pass WithTestClusterTLS/WithOracleClusterTLS
+// to point at clusters that require client-cert or username/password auth.
+// It returns an error rather than panicking, so a misconfigured or
+// unreachable cluster is an ordinary failure for the caller to handle.
+func NewSession(testClusterHost, oracleClusterHost string, opts ...SessionOption) (*Session, error) {
+	var cfg SessionConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	testCluster := buildCluster(testClusterHost, cfg.TestTLS)
+	testSession, err := testCluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create session for test cluster %s: %v", testClusterHost, err)
+	}
+
+	var oracleSession *gocql.Session
+	if oracleClusterHost != "" {
+		oracleCluster := buildCluster(oracleClusterHost, cfg.OracleTLS)
+		oracleSession, err = oracleCluster.CreateSession()
+		if err != nil {
+			testSession.Close()
+			return nil, fmt.Errorf("unable to create session for oracle cluster %s: %v", oracleClusterHost, err)
+		}
+	}
+
+	return &Session{
+		test:   testSession,
+		oracle: oracleSession,
+	}, nil
+}
+
+// Session wraps the gocql sessions for the test and oracle clusters.
+type Session struct {
+	test   *gocql.Session
+	oracle *gocql.Session
+}
+
+// Mutate executes stmt against the test cluster.
+func (s *Session) Mutate(stmt string, values ...interface{}) error {
+	return s.test.Query(stmt, values...).Exec()
+}
+
+// Check executes stmt against the test cluster and, when an oracle cluster
+// is configured, against the oracle cluster, comparing the results row by
+// row. It returns ErrReadNoDataReturned if the test cluster has nothing
+// for the partition, and a descriptive error on the first row count or
+// value mismatch between the two clusters.
+func (s *Session) Check(table Table, stmt string, values ...interface{}) error {
+	testRows, err := s.test.Query(stmt, values...).Iter().SliceMap()
+	if err != nil {
+		return err
+	}
+	if len(testRows) == 0 {
+		return ErrReadNoDataReturned
+	}
+	if s.oracle == nil {
+		return nil
+	}
+
+	oracleRows, err := s.oracle.Query(stmt, values...).Iter().SliceMap()
+	if err != nil {
+		return err
+	}
+	if len(testRows) != len(oracleRows) {
+		return fmt.Errorf("table %s: test cluster returned %d rows, oracle cluster returned %d", table.Name, len(testRows), len(oracleRows))
+	}
+	for i := range testRows {
+		if !reflect.DeepEqual(testRows[i], oracleRows[i]) {
+			return fmt.Errorf("table %s: row %d mismatch: test=%v oracle=%v", table.Name, i, testRows[i], oracleRows[i])
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying gocql sessions.
+func (s *Session) Close() {
+	s.test.Close()
+	if s.oracle != nil {
+		s.oracle.Close()
+	}
+}